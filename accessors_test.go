@@ -0,0 +1,39 @@
+package tracelite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNameStartedAtAndStatus(t *testing.T) {
+	before := time.Now()
+	trace := NewTrace("test_trace")
+	after := time.Now()
+
+	if trace.Name() != "test_trace" {
+		t.Errorf("expected Name to be 'test_trace', got %s", trace.Name())
+	}
+	if trace.StartedAt().Before(before) || trace.StartedAt().After(after) {
+		t.Errorf("expected StartedAt to be set at construction, got %s", trace.StartedAt())
+	}
+	if trace.Status() != StatusOK {
+		t.Errorf("expected a fresh trace to be StatusOK, got %d", trace.Status())
+	}
+
+	trace.SetError()
+	if trace.Status() != StatusError {
+		t.Errorf("expected SetError to set StatusError, got %d", trace.Status())
+	}
+}
+
+func TestTotalCost(t *testing.T) {
+	trace := NewTrace("test_trace")
+	trace.TraceOn()
+	trace.BeginTrace("subtrace1", nil)
+	time.Sleep(10 * time.Millisecond)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	if trace.TotalCost() <= 0 {
+		t.Errorf("expected a positive total cost, got %d", trace.TotalCost())
+	}
+}
@@ -0,0 +1,178 @@
+package debug
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/OC0311/tracelite"
+)
+
+// latencyBucket bounds are in milliseconds; the last bucket has no upper
+// bound. Errors are tracked separately from these buckets regardless of
+// their cost.
+var latencyBucketBounds = []int64{10, 100, 1000}
+
+// latencyBucketLabels mirrors latencyBucketBounds, plus a trailing label for
+// anything slower than the last bound.
+var latencyBucketLabels = []string{"<10ms", "<100ms", "<1s", ">=1s"}
+
+// familySummary is the per-family row rendered by the index page.
+type familySummary struct {
+	Name     string
+	Active   int
+	Buckets  []int
+	Errors   int
+	Finished int
+}
+
+func bucketIndex(totalCostMs int64) int {
+	for i, bound := range latencyBucketBounds {
+		if totalCostMs < bound {
+			return i
+		}
+	}
+	return len(latencyBucketBounds)
+}
+
+func summarize(name string, active, finished []*tracelite.Trace) familySummary {
+	s := familySummary{
+		Name:     name,
+		Active:   len(active),
+		Buckets:  make([]int, len(latencyBucketLabels)),
+		Finished: len(finished),
+	}
+	for _, tr := range finished {
+		if tr.Status() == tracelite.StatusError {
+			s.Errors++
+			continue
+		}
+		s.Buckets[bucketIndex(tr.TotalCost())]++
+	}
+	return s
+}
+
+// Handler serves /debug/traces on DefaultRegistry: an HTML page listing
+// every known family with in-flight/finished counts and latency buckets,
+// or, given a ?family= query parameter, a detail view of that family's
+// traces.
+func Handler() http.Handler {
+	return DefaultRegistry.Handler()
+}
+
+// Handler serves /debug/traces for r.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if name := req.URL.Query().Get("family"); name != "" {
+			r.renderFamily(w, name)
+			return
+		}
+		r.renderIndex(w)
+	})
+}
+
+// EventsHandler serves /debug/events on DefaultRegistry: given a ?family=
+// query parameter, it renders the Collect() span list of that family's
+// most recently finished trace (or its most recently registered active
+// trace, if none have finished yet).
+func EventsHandler() http.Handler {
+	return DefaultRegistry.EventsHandler()
+}
+
+// EventsHandler serves /debug/events for r.
+func (r *Registry) EventsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		name := req.URL.Query().Get("family")
+		if name == "" {
+			http.Error(w, "missing family query parameter", http.StatusBadRequest)
+			return
+		}
+		tr, ok := r.familyFor(name).newest()
+		if !ok {
+			http.Error(w, "unknown family", http.StatusNotFound)
+			return
+		}
+		result := tr.Collect()
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if result == nil {
+			fmt.Fprint(w, "null")
+			return
+		}
+		fmt.Fprint(w, result.ToString())
+	})
+}
+
+func (r *Registry) renderIndex(w http.ResponseWriter) {
+	names := r.familyNames()
+	sort.Strings(names)
+
+	summaries := make([]familySummary, 0, len(names))
+	for _, name := range names {
+		active, finished := r.familyFor(name).snapshot()
+		summaries = append(summaries, summarize(name, active, finished))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, struct {
+		Families     []familySummary
+		BucketLabels []string
+		GeneratedAt  time.Time
+	}{
+		Families:     summaries,
+		BucketLabels: latencyBucketLabels,
+		GeneratedAt:  time.Now(),
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (r *Registry) renderFamily(w http.ResponseWriter, name string) {
+	active, finished := r.familyFor(name).snapshot()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := familyTemplate.Execute(w, struct {
+		Name     string
+		Active   []*tracelite.Trace
+		Finished []*tracelite.Trace
+	}{
+		Name:     name,
+		Active:   active,
+		Finished: finished,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html><head><title>tracelite debug</title></head>
+<body>
+<h1>tracelite families</h1>
+<p>generated {{.GeneratedAt}}</p>
+<table border="1" cellpadding="4">
+<tr><th>family</th><th>active</th><th>finished</th>{{range .BucketLabels}}<th>{{.}}</th>{{end}}<th>errors</th></tr>
+{{range .Families}}
+<tr>
+<td><a href="?family={{.Name}}">{{.Name}}</a></td>
+<td>{{.Active}}</td>
+<td>{{.Finished}}</td>
+{{range .Buckets}}<td>{{.}}</td>{{end}}
+<td>{{.Errors}}</td>
+</tr>
+{{end}}
+</table>
+</body></html>
+`))
+
+var familyTemplate = template.Must(template.New("family").Parse(`<!DOCTYPE html>
+<html><head><title>tracelite debug: {{.Name}}</title></head>
+<body>
+<h1>{{.Name}}</h1>
+<p><a href="/debug/traces">&laquo; back</a></p>
+<h2>active ({{len .Active}})</h2>
+<ul>{{range .Active}}<li>{{.Name}} started {{.StartedAt}}</li>{{end}}</ul>
+<h2>finished ({{len .Finished}})</h2>
+<ul>{{range .Finished}}<li>{{.Name}} cost={{.TotalCost}}ms status={{.Status}}</li>{{end}}</ul>
+</body></html>
+`))
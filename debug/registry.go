@@ -0,0 +1,158 @@
+// Package debug registers /debug/traces and /debug/events HTTP handlers,
+// modeled on golang.org/x/net/trace, that expose a process-wide view of
+// in-flight and recently-finished tracelite traces for live inspection
+// without any external dependencies.
+package debug
+
+import (
+	"sync"
+
+	"github.com/OC0311/tracelite"
+)
+
+// DefaultRingSize is the number of finished traces retained per family by
+// NewRegistry when no explicit size is given.
+const DefaultRingSize = 50
+
+// finishedEntry pairs a finished trace with the sequence number it was
+// finished at, so the ring buffer's logical newest entry can still be found
+// once it has wrapped (its slice index no longer tracks insertion order).
+type finishedEntry struct {
+	tr  *tracelite.Trace
+	seq int64
+}
+
+// family tracks the active and recently-finished traces for one trace name.
+type family struct {
+	mu       sync.Mutex
+	seq      int64
+	active   map[*tracelite.Trace]int64 // value is the registration/finish sequence, for finding the most recent one
+	finished []finishedEntry            // ring buffer; zero value unused, oldest overwritten first
+	next     int
+	size     int
+}
+
+func newFamily(size int) *family {
+	return &family{
+		active: make(map[*tracelite.Trace]int64),
+		size:   size,
+	}
+}
+
+func (f *family) register(tr *tracelite.Trace) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	f.active[tr] = f.seq
+}
+
+func (f *family) finish(tr *tracelite.Trace) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.active, tr)
+	f.seq++
+	entry := finishedEntry{tr: tr, seq: f.seq}
+	if len(f.finished) < f.size {
+		f.finished = append(f.finished, entry)
+		return
+	}
+	f.finished[f.next] = entry
+	f.next = (f.next + 1) % f.size
+}
+
+func (f *family) snapshot() (active []*tracelite.Trace, finished []*tracelite.Trace) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for tr := range f.active {
+		active = append(active, tr)
+	}
+	for _, e := range f.finished {
+		finished = append(finished, e.tr)
+	}
+	return active, finished
+}
+
+// newest returns the most recently finished trace in f, or, if none have
+// finished yet, the most recently registered active trace. It reports false
+// if f has neither.
+func (f *family) newest() (tr *tracelite.Trace, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	best := int64(-1)
+	for _, e := range f.finished {
+		if e.seq > best {
+			best = e.seq
+			tr = e.tr
+		}
+	}
+	if tr != nil {
+		return tr, true
+	}
+	for cand, seq := range f.active {
+		if seq > best {
+			best = seq
+			tr = cand
+		}
+	}
+	return tr, tr != nil
+}
+
+// Registry is a process-wide collection of tracelite traces grouped by
+// family (Trace.Name), backing the /debug/traces and /debug/events HTTP
+// handlers.
+type Registry struct {
+	mu       sync.Mutex
+	ringSize int
+	families map[string]*family
+}
+
+// NewRegistry creates a Registry that retains up to ringSize finished traces
+// per family. A ringSize of 0 uses DefaultRingSize.
+func NewRegistry(ringSize int) *Registry {
+	if ringSize <= 0 {
+		ringSize = DefaultRingSize
+	}
+	return &Registry{
+		ringSize: ringSize,
+		families: make(map[string]*family),
+	}
+}
+
+// DefaultRegistry is the Registry backing Handler and EventsHandler.
+var DefaultRegistry = NewRegistry(DefaultRingSize)
+
+func (r *Registry) familyFor(name string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.families[name]
+	if !ok {
+		f = newFamily(r.ringSize)
+		r.families[name] = f
+	}
+	return f
+}
+
+// Register adds tr to the active set of its family (Trace.Name). Call
+// Finish when tr completes to move it into the family's finished ring
+// buffer.
+func (r *Registry) Register(tr *tracelite.Trace) {
+	r.familyFor(tr.Name()).register(tr)
+}
+
+// Finish moves tr from its family's active set into its finished ring
+// buffer, overwriting the oldest finished trace once the buffer is full.
+// Traces that were never Registered are simply added to the ring buffer.
+func (r *Registry) Finish(tr *tracelite.Trace) {
+	r.familyFor(tr.Name()).finish(tr)
+}
+
+// familyNames returns the registry's family names in no particular order.
+func (r *Registry) familyNames() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	return names
+}
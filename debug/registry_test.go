@@ -0,0 +1,100 @@
+package debug
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OC0311/tracelite"
+)
+
+func TestRegisterAndFinish(t *testing.T) {
+	r := NewRegistry(2)
+
+	tr := tracelite.NewTrace("my_family")
+	r.Register(tr)
+
+	active, finished := r.familyFor("my_family").snapshot()
+	if len(active) != 1 || len(finished) != 0 {
+		t.Fatalf("expected 1 active, 0 finished, got %d/%d", len(active), len(finished))
+	}
+
+	r.Finish(tr)
+	active, finished = r.familyFor("my_family").snapshot()
+	if len(active) != 0 || len(finished) != 1 {
+		t.Fatalf("expected 0 active, 1 finished, got %d/%d", len(active), len(finished))
+	}
+}
+
+func TestFinishRingBufferOverwritesOldest(t *testing.T) {
+	r := NewRegistry(2)
+
+	for i := 0; i < 3; i++ {
+		tr := tracelite.NewTrace("ring_family")
+		r.Finish(tr)
+	}
+
+	_, finished := r.familyFor("ring_family").snapshot()
+	if len(finished) != 2 {
+		t.Fatalf("expected ring buffer capped at 2, got %d", len(finished))
+	}
+}
+
+func TestHandlerRendersFamilies(t *testing.T) {
+	r := NewRegistry(DefaultRingSize)
+	tr := tracelite.NewTrace("http_family")
+	tr.SetError()
+	r.Finish(tr)
+
+	req := httptest.NewRequest("GET", "/debug/traces", nil)
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "http_family") {
+		t.Errorf("expected index page to mention the family, got: %s", w.Body.String())
+	}
+}
+
+func TestFamilyNewestFinishedSurvivesRingWrap(t *testing.T) {
+	f := newFamily(2)
+
+	a := tracelite.NewTrace("wrap_family")
+	b := tracelite.NewTrace("wrap_family")
+	c := tracelite.NewTrace("wrap_family")
+	f.finish(a)
+	f.finish(b)
+	f.finish(c) // wraps the size-2 ring, overwriting a
+
+	tr, ok := f.newest()
+	if !ok || tr != c {
+		t.Errorf("expected newest to return the most recently finished trace after wrap, got %v (ok=%v)", tr, ok)
+	}
+}
+
+func TestFamilyNewestPrefersMostRecentlyRegisteredActive(t *testing.T) {
+	f := newFamily(DefaultRingSize)
+
+	a := tracelite.NewTrace("active_family")
+	b := tracelite.NewTrace("active_family")
+	f.register(a)
+	f.register(b)
+
+	tr, ok := f.newest()
+	if !ok || tr != b {
+		t.Errorf("expected newest to return the most recently registered active trace, got %v (ok=%v)", tr, ok)
+	}
+}
+
+func TestEventsHandlerRequiresFamily(t *testing.T) {
+	r := NewRegistry(DefaultRingSize)
+	req := httptest.NewRequest("GET", "/debug/events", nil)
+	w := httptest.NewRecorder()
+	r.EventsHandler().ServeHTTP(w, req)
+
+	if w.Code != 400 {
+		t.Errorf("expected 400 for a missing family, got %d", w.Code)
+	}
+}
@@ -9,36 +9,117 @@ import (
 // Trace represents a trace instance that can track multiple sub-traces
 type Trace struct {
 	sync.RWMutex
-	name      string         // Name of the trace
-	tags      map[string]any // Key-value pairs for additional trace information
-	traceList map[string]trace // Map of sub-traces
-	traceAt   time.Time     // Time when trace was created
-	status    int           // Status of the trace
-	totalCost int64         // Total time cost in milliseconds
-	openTrace bool          // Flag to control if tracing is enabled
+	name           string           // Name of the trace
+	tags           map[string]any   // Key-value pairs for additional trace information
+	fields         []Field          // Structured detail attached via Fields
+	traceList      map[string]trace // Map of sub-traces
+	traceAt        time.Time        // Time when trace was created
+	status         int              // Status of the trace
+	totalCost      int64            // Total time cost in milliseconds
+	openTrace      bool             // Flag to control if tracing is enabled
+	traceThreshold time.Duration    // Collect discards the trace entirely when TotalCost is below this; 0 disables the gate
+	stepThreshold  time.Duration    // Collect omits a span from the rendered list when its cost is at or below this; 0 disables the gate
+	exporter       Exporter         // Receives the Finish result, if set via WithExporter
+	sampler        Sampler          // Decides, once per sub-trace, whether it records anything; nil means always sample
+	finished       bool             // True once Finish has run its exporter call
+}
+
+// Exporter ships a collected TraceResult to an external system. Sub-packages
+// such as otel implement this to bridge tracelite into other observability
+// stacks.
+type Exporter interface {
+	Export(*TraceResult) error
+}
+
+// Option configures a Trace at construction time via NewTrace.
+type Option func(*Trace)
+
+// WithTraceThreshold sets a minimum TotalCost below which Collect discards
+// the trace entirely (returns nil). This lets high-volume services leave
+// tracing on for every request and only pay the rendering cost for the slow
+// ones, mirroring etcd's warnApplyDuration pattern.
+func WithTraceThreshold(d time.Duration) Option {
+	return func(t *Trace) {
+		t.traceThreshold = d
+	}
+}
+
+// WithStepThreshold sets a minimum span cost at or below which Collect omits
+// the span from the rendered list, keeping fast, uninteresting spans out of
+// the output while still counting them towards TotalCost.
+func WithStepThreshold(d time.Duration) Option {
+	return func(t *Trace) {
+		t.stepThreshold = d
+	}
+}
+
+// WithExporter registers exporter so the first Finish call that
+// WithTraceThreshold does not discard automatically ships the resulting
+// TraceResult to it (best-effort; Export errors are ignored).
+func WithExporter(exporter Exporter) Option {
+	return func(t *Trace) {
+		t.exporter = exporter
+	}
+}
+
+// WithSampler installs sampler, which decides once per sub-trace, at
+// BeginTrace or StartStep, whether it records anything. The default (no
+// WithSampler option) always samples, matching tracelite's original
+// behavior.
+func WithSampler(sampler Sampler) Option {
+	return func(t *Trace) {
+		t.sampler = sampler
+	}
+}
+
+// shouldSampleLocked reports whether a new sub-trace should be created. It
+// must be called with t's lock held.
+func (t *Trace) shouldSampleLocked() bool {
+	if t.sampler == nil {
+		return true
+	}
+	return t.sampler.ShouldSample()
 }
 
 // trace represents a single sub-trace with its spans
 type trace struct {
 	name string         // Name of the sub-trace
 	tags map[string]any // Key-value pairs for additional trace information
-	list []span        // List of spans in this trace
-	cost int64         // Total time cost of this trace
+	list []span         // List of spans in this trace
 }
 
 // span represents a single time point in a trace
 type span struct {
-	action        string    // Action name or description
-	extensionInfo string    // Additional information about the action
-	cost          int64     // Time cost in milliseconds
-	martAt        time.Time // Timestamp when the span was marked
+	id            string        // Unique id of this span, set when created via StartStep
+	parentID      string        // Id of the enclosing step's span, empty for top-level spans
+	depth         int           // Nesting depth within the sub-trace, 0 for top-level spans
+	action        string        // Action name or description
+	extensionInfo string        // Additional information about the action
+	lazyExt       func() string // If set (via LazyMark), computes extensionInfo lazily at Collect time instead
+	fields        []Field       // Structured key/value pairs attached via StartStep
+	cost          int64         // Time cost in milliseconds
+	hasCost       bool          // True when cost was recorded directly (StartStep/End) rather than diffed from the previous span
+	martAt        time.Time     // Timestamp when the span was marked
 }
 
+// Field is a structured key/value pair that can be attached to a step.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Status values reported by Trace.Status.
+const (
+	StatusOK    int = iota // The trace has not been marked as failed
+	StatusError            // SetError was called on the trace
+)
+
 // TraceResult represents the final result of a trace collection
 type TraceResult struct {
-	TraceName string         `json:"trace_name"` // Name of the main trace
-	Tags      map[string]any `json:"tags"`      // Tags associated with the trace
-	TotalCost int64                             // Total time cost of all traces
+	TraceName string                       `json:"trace_name"`       // Name of the main trace
+	Tags      map[string]any               `json:"tags"`             // Tags associated with the trace
+	Fields    []Field                      `json:"fields,omitempty"` // Structured detail attached via Trace.Fields
+	TotalCost int64                        // Total time cost of all traces
 	TraceSet  []map[string]TraceResultItem `json:"trace_set"` // Collection of trace results
 }
 
@@ -58,13 +139,19 @@ type TraceResultItem struct {
 	List      [][]interface{}        `json:"list"`       // List of span information
 }
 
-// NewTrace creates a new Trace instance with the given name
-func NewTrace(name string) *Trace {
-	return &Trace{
+// NewTrace creates a new Trace instance with the given name, applying any
+// options such as WithTraceThreshold or WithStepThreshold.
+func NewTrace(name string, opts ...Option) *Trace {
+	t := &Trace{
 		name:      name,
 		tags:      make(map[string]interface{}),
 		traceList: make(map[string]trace),
+		traceAt:   time.Now(),
+	}
+	for _, opt := range opts {
+		opt(t)
 	}
+	return t
 }
 
 // BeginTrace starts a new trace with the given name and tags
@@ -78,6 +165,9 @@ func (t *Trace) BeginTrace(traceName string, tags map[string]interface{}) {
 		tags = make(map[string]interface{})
 	}
 	if _, ok := t.traceList[traceName]; !ok {
+		if !t.shouldSampleLocked() {
+			return
+		}
 		t.traceList[traceName] = trace{
 			name: traceName,
 			tags: tags,
@@ -101,6 +191,77 @@ func (t *Trace) SetTags(tags map[string]interface{}) {
 	t.tags = tags
 }
 
+// Name returns the trace's family name, used by sub-packages such as debug
+// to group traces.
+func (t *Trace) Name() string {
+	t.RLock()
+	defer t.RUnlock()
+	return t.name
+}
+
+// StartedAt returns the time the trace was created.
+func (t *Trace) StartedAt() time.Time {
+	t.RLock()
+	defer t.RUnlock()
+	return t.traceAt
+}
+
+// Status returns the trace's current status, StatusOK unless SetError has
+// been called.
+func (t *Trace) Status() int {
+	t.RLock()
+	defer t.RUnlock()
+	return t.status
+}
+
+// SetError marks the trace as failed. Sub-packages such as debug use this to
+// bucket traces by success/failure when rendering family summaries.
+func (t *Trace) SetError() {
+	t.Lock()
+	defer t.Unlock()
+	t.status = StatusError
+}
+
+// TotalCost reports the cost that the most recent Collect would compute for
+// TraceResult.TotalCost, without honoring WithTraceThreshold or building a
+// full TraceResult. It's intended for lightweight summaries such as the
+// debug package's family listings.
+//
+// Only top-level spans (those with no parentID) count towards the total: a
+// StartStep span nested inside another already has its elapsed time folded
+// into its parent's, so adding both would double-count the overlap.
+func (t *Trace) TotalCost() int64 {
+	t.RLock()
+	defer t.RUnlock()
+	var total int64
+	for _, v := range t.traceList {
+		for i, s := range v.list {
+			if s.parentID != "" {
+				continue
+			}
+			if s.hasCost {
+				total += s.cost
+				continue
+			}
+			if i == 0 {
+				continue
+			}
+			total += s.martAt.Sub(v.list[i-1].martAt).Milliseconds()
+		}
+	}
+	return total
+}
+
+// Fields attaches structured key/value detail to the trace, to be rendered
+// alongside its tags on Collect. Unlike SetTags it is additive and safe to
+// call repeatedly as more detail becomes available, mirroring etcd's
+// Trace.AddField.
+func (t *Trace) Fields(fields ...Field) {
+	t.Lock()
+	defer t.Unlock()
+	t.fields = append(t.fields, fields...)
+}
+
 // TraceOn enables tracing
 func (t *Trace) TraceOn() {
 	t.Lock()
@@ -135,16 +296,45 @@ func (t *Trace) Mark(traceName, action, ext string) {
 	t.traceList[traceName] = s
 }
 
-// Collect gathers all trace information and returns a TraceResult
-func (t *Trace) Collect() *TraceResult {
+// LazyMark adds a new span to traceName whose extensionInfo is computed
+// lazily: extFn is only called once the trace is collected via Collect, so
+// callers can defer expensive fmt.Sprintf/JSON marshaling of extensionInfo
+// until they know the trace will actually be reported, mirroring
+// golang.org/x/net/trace's LazyPrintf.
+func (t *Trace) LazyMark(traceName, action string, extFn func() string) {
+	now := time.Now()
 	t.Lock()
 	defer t.Unlock()
 	if !t.openTrace {
-		return nil
+		return
+	}
+	if _, ok := t.traceList[traceName]; !ok {
+		return
 	}
-	result := &TraceResult{
+	s := t.traceList[traceName]
+	s.list = append(t.traceList[traceName].list, span{
+		action:  action,
+		lazyExt: extFn,
+		martAt:  now,
+	})
+	t.traceList[traceName] = s
+}
+
+// collect gathers all trace information into a TraceResult and reports
+// whether WithTraceThreshold would discard it, without exporting or honoring
+// that gate itself. The two are split apart so that callers such as
+// LogIfLong can inspect result.TotalCost against their own threshold even
+// when the trace's own WithTraceThreshold would otherwise drop it.
+func (t *Trace) collect() (result *TraceResult, discard bool) {
+	t.Lock()
+	defer t.Unlock()
+	if !t.openTrace {
+		return nil, true
+	}
+	result = &TraceResult{
 		TraceName: t.name,
 		Tags:      t.tags,
+		Fields:    t.fields,
 		TraceSet:  make([]map[string]TraceResultItem, 0),
 	}
 
@@ -156,16 +346,40 @@ func (t *Trace) Collect() *TraceResult {
 		traceItem := TraceResultItem{
 			Tags: v.tags,
 		}
-		for i := 1; i < len(v.list); i++ {
-			currentSpan := v.list[i]
-			preSpan := v.list[i-1]
-			cost := currentSpan.martAt.Sub(preSpan.martAt).Milliseconds()
-			traceCost += cost
+		for i, currentSpan := range v.list {
+			var cost int64
+			if currentSpan.hasCost {
+				// Spans recorded via StartStep/End carry their own elapsed cost.
+				cost = currentSpan.cost
+			} else {
+				if i == 0 {
+					// The first span of a BeginTrace/Mark sub-trace is the "Begin"
+					// marker itself and has nothing to diff against.
+					continue
+				}
+				preSpan := v.list[i-1]
+				cost = currentSpan.martAt.Sub(preSpan.martAt).Milliseconds()
+			}
+			if currentSpan.parentID == "" {
+				// Only top-level spans count towards traceCost: a nested
+				// StartStep span's elapsed time is already folded into its
+				// parent's, so summing both would double-count the overlap.
+				traceCost += cost
+			}
+			if t.stepThreshold > 0 && time.Duration(cost)*time.Millisecond <= t.stepThreshold {
+				continue
+			}
+			extensionInfo := currentSpan.extensionInfo
+			if currentSpan.lazyExt != nil {
+				extensionInfo = currentSpan.lazyExt()
+			}
 			item := []interface{}{
 				currentSpan.action,
 				cost,
-				currentSpan.extensionInfo,
+				extensionInfo,
 				currentSpan.martAt,
+				currentSpan.parentID,
+				currentSpan.depth,
 			}
 			traceItem.List = append(traceItem.List, item)
 		}
@@ -177,6 +391,52 @@ func (t *Trace) Collect() *TraceResult {
 		result.TraceSet = append(result.TraceSet, traceItemSet)
 		result.TotalCost += traceCost
 	}
+
+	discard = t.traceThreshold > 0 && time.Duration(result.TotalCost)*time.Millisecond < t.traceThreshold
+	return result, discard
+}
+
+// Collect gathers all trace information and returns a TraceResult, or nil if
+// tracing is off or the trace's WithTraceThreshold discards it. Collect is a
+// read-style call: it never exports, and is safe to call as many times as
+// needed. Use Finish instead at the point a trace is done, to ship it to the
+// configured Exporter.
+func (t *Trace) Collect() *TraceResult {
+	result, discard := t.collect()
+	if discard {
+		return nil
+	}
+	return result
+}
+
+// Finish collects the trace and, the first time a call is not discarded by
+// WithTraceThreshold, ships the result to the Exporter installed via
+// WithExporter (best-effort; Export errors are ignored). A discarded call
+// returns nil and exports nothing. It is safe to call more than once; later calls still
+// collect and return a fresh result but do not export again, so
+// instrumentation that both logs and exports a trace doesn't ship it to the
+// Exporter twice. Each call still re-runs LazyMark's extFn, the same as
+// Collect and LogIfLong.
+func (t *Trace) Finish() *TraceResult {
+	result, discard := t.collect()
+
+	if discard {
+		// A discarded call hasn't actually shipped anything, so it must not
+		// latch t.finished: a later call, once the trace qualifies, still
+		// needs to export.
+		return nil
+	}
+
+	t.Lock()
+	alreadyFinished := t.finished
+	t.finished = true
+	exporter := t.exporter
+	t.Unlock()
+
+	if !alreadyFinished && exporter != nil {
+		// Export runs outside the lock, since it may perform network I/O.
+		exporter.Export(result)
+	}
 	return result
 }
 
@@ -188,3 +448,26 @@ func (t *Trace) CollectToString(fmtFunc func(*TraceResult) string) string {
 	}
 	return fmtFunc(result)
 }
+
+// Logger is the minimal logging interface LogIfLong writes to; *log.Logger
+// satisfies it.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// LogIfLong collects the trace and writes a one-line summary to logger when
+// the collected TotalCost is at least threshold, regardless of the trace's own
+// WithTraceThreshold setting. This gives call sites an ad-hoc threshold
+// check without reconfiguring the trace, mirroring etcd's
+// warnApplyDuration helper.
+func (t *Trace) LogIfLong(logger Logger, threshold time.Duration) {
+	result, _ := t.collect()
+	if result == nil {
+		return
+	}
+	if time.Duration(result.TotalCost)*time.Millisecond < threshold {
+		return
+	}
+	logger.Printf("trace[%s] took too long, total_cost=%dms threshold=%s: %s",
+		result.TraceName, result.TotalCost, threshold, result.ToString())
+}
@@ -131,8 +131,69 @@ func TestCollectToString(t *testing.T) {
 	result = trace.CollectToString(func(tr *TraceResult) string {
 		return "should not reach here"
 	})
-	
+
 	if result != "" {
 		t.Error("Expected empty string when trace is off")
 	}
+}
+
+type countingExporter struct {
+	calls int
+}
+
+func (e *countingExporter) Export(*TraceResult) error {
+	e.calls++
+	return nil
+}
+
+func TestFinishExportsOnlyOnce(t *testing.T) {
+	exporter := &countingExporter{}
+	trace := NewTrace("test_trace", WithExporter(exporter))
+	trace.TraceOn()
+	trace.BeginTrace("subtrace1", nil)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	trace.Finish()
+	trace.Finish()
+	if exporter.calls != 1 {
+		t.Errorf("expected Finish to export exactly once across repeated calls, got %d", exporter.calls)
+	}
+}
+
+func TestFinishDiscardedByThresholdDoesNotLatch(t *testing.T) {
+	exporter := &countingExporter{}
+	trace := NewTrace("test_trace", WithTraceThreshold(time.Hour), WithExporter(exporter))
+	trace.TraceOn()
+	trace.BeginTrace("subtrace1", nil)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	if result := trace.Finish(); result != nil {
+		t.Fatalf("expected Finish to discard below WithTraceThreshold, got %+v", result)
+	}
+	if exporter.calls != 0 {
+		t.Fatalf("expected no export while below threshold, got %d calls", exporter.calls)
+	}
+
+	// Simulate the trace crossing the threshold before a later Finish call;
+	// a discarded Finish must not have permanently latched t.finished.
+	trace.Mark("subtrace1", "action2", "ext2")
+	trace.traceThreshold = 0
+	trace.Finish()
+	if exporter.calls != 1 {
+		t.Errorf("expected the later, qualifying Finish to export, got %d calls", exporter.calls)
+	}
+}
+
+func TestCollectDoesNotExport(t *testing.T) {
+	exporter := &countingExporter{}
+	trace := NewTrace("test_trace", WithExporter(exporter))
+	trace.TraceOn()
+	trace.BeginTrace("subtrace1", nil)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	trace.Collect()
+	trace.CollectToString(func(tr *TraceResult) string { return "" })
+	if exporter.calls != 0 {
+		t.Errorf("expected Collect/CollectToString to be read-only and never export, got %d calls", exporter.calls)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,207 @@
+// Package otel bridges tracelite into the OpenTelemetry ecosystem: it
+// converts a *tracelite.TraceResult into OpenTelemetry spans and ships them
+// via OTLP/gRPC or OTLP/HTTP, so tracelite users can forward their traces to
+// Datadog, Jaeger, Tempo, or any other OTLP-compatible backend without
+// rewriting instrumentation.
+package otel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OC0311/tracelite"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Exporter converts tracelite TraceResults into OpenTelemetry spans and
+// exports them to an OTLP collector. It satisfies tracelite.Exporter, so it
+// can be installed on a *tracelite.Trace via tracelite.WithExporter.
+type Exporter struct {
+	provider *sdktrace.TracerProvider
+	tracer   oteltrace.Tracer
+}
+
+// config holds the options accumulated by NewExporter.
+type config struct {
+	protocol string // "grpc" (default) or "http"
+	endpoint string
+}
+
+// Option configures a NewExporter call.
+type Option func(*config)
+
+// WithGRPCEndpoint selects the OTLP/gRPC protocol (the default) and targets
+// the given collector endpoint, e.g. "localhost:4317".
+func WithGRPCEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.protocol = "grpc"
+		c.endpoint = endpoint
+	}
+}
+
+// WithHTTPEndpoint selects the OTLP/HTTP protocol and targets the given
+// collector endpoint, e.g. "localhost:4318".
+func WithHTTPEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.protocol = "http"
+		c.endpoint = endpoint
+	}
+}
+
+// NewExporter dials an OTLP collector (gRPC by default; see WithHTTPEndpoint)
+// and returns an Exporter ready to have TraceResults handed to Export.
+func NewExporter(ctx context.Context, opts ...Option) (*Exporter, error) {
+	cfg := &config{protocol: "grpc"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var (
+		client otlptrace.Client
+	)
+	switch cfg.protocol {
+	case "http":
+		var httpOpts []otlptracehttp.Option
+		if cfg.endpoint != "" {
+			httpOpts = append(httpOpts, otlptracehttp.WithEndpoint(cfg.endpoint))
+		}
+		client = otlptracehttp.NewClient(httpOpts...)
+	default:
+		var grpcOpts []otlptracegrpc.Option
+		if cfg.endpoint != "" {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithEndpoint(cfg.endpoint))
+		}
+		client = otlptracegrpc.NewClient(grpcOpts...)
+	}
+
+	exp, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("tracelite/otel: dial OTLP collector: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp))
+	return &Exporter{
+		provider: provider,
+		tracer:   provider.Tracer("github.com/OC0311/tracelite"),
+	}, nil
+}
+
+// Export converts result into OpenTelemetry spans and ships them to the
+// configured OTLP collector. Each sub-trace in result.TraceSet becomes a
+// span named after its key, with its tags as attributes and result's own
+// tags and fields as attributes on an enclosing root span named after
+// result.TraceName. Each entry in a sub-trace's span list becomes a child
+// span whose start time is the entry's recorded timestamp and whose
+// duration runs to the next entry's timestamp (or, for the last entry, its
+// own recorded cost), named after its action with extensionInfo attached as
+// an attribute.
+func (e *Exporter) Export(result *tracelite.TraceResult) error {
+	ctx := context.Background()
+
+	rootAttrs := attributesFromTags(result.Tags)
+	for _, f := range result.Fields {
+		rootAttrs = append(rootAttrs, attributeFromField(f))
+	}
+	rootCtx, rootSpan := e.tracer.Start(ctx, result.TraceName,
+		oteltrace.WithTimestamp(time.Now().Add(-time.Duration(result.TotalCost)*time.Millisecond)),
+		oteltrace.WithAttributes(rootAttrs...),
+	)
+
+	for _, set := range result.TraceSet {
+		for name, item := range set {
+			exportSubTrace(rootCtx, e.tracer, name, item)
+		}
+	}
+
+	rootSpan.End()
+	return nil
+}
+
+// exportSubTrace renders one TraceResultItem as a span (named name, tagged
+// with item.Tags) whose children are the span entries in item.List.
+func exportSubTrace(ctx context.Context, tracer oteltrace.Tracer, name string, item tracelite.TraceResultItem) {
+	start, _ := spanTimestamp(item.List, 0)
+	subCtx, subSpan := tracer.Start(ctx, name,
+		oteltrace.WithTimestamp(start),
+		oteltrace.WithAttributes(attributesFromTags(item.Tags)...),
+	)
+
+	for i, entry := range item.List {
+		action, _ := entry[0].(string)
+		extensionInfo, _ := entry[2].(string)
+		entryStart, _ := spanTimestamp(item.List, i)
+
+		var entryEnd time.Time
+		if end, ok := spanTimestamp(item.List, i+1); ok {
+			entryEnd = end
+		} else if costMs, ok := entry[1].(int64); ok {
+			entryEnd = entryStart.Add(time.Duration(costMs) * time.Millisecond)
+		} else {
+			entryEnd = entryStart
+		}
+
+		_, childSpan := tracer.Start(subCtx, action,
+			oteltrace.WithTimestamp(entryStart),
+			oteltrace.WithAttributes(attribute.String("extension_info", extensionInfo)),
+		)
+		childSpan.End(oteltrace.WithTimestamp(entryEnd))
+	}
+
+	end, _ := spanTimestamp(item.List, len(item.List)-1)
+	subSpan.End(oteltrace.WithTimestamp(end))
+}
+
+// spanTimestamp extracts the martAt timestamp tracelite recorded at index i
+// of a TraceResultItem.List (see Trace.Collect), returning ok=false past the
+// end of the list.
+func spanTimestamp(list [][]interface{}, i int) (time.Time, bool) {
+	if i < 0 || i >= len(list) {
+		return time.Now(), false
+	}
+	ts, ok := list[i][3].(time.Time)
+	if !ok {
+		return time.Now(), false
+	}
+	return ts, true
+}
+
+// attributesFromTags converts a tracelite tag map into OpenTelemetry
+// attributes, falling back to fmt.Sprintf for types attribute.KeyValue
+// doesn't natively support.
+func attributesFromTags(tags map[string]any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(tags))
+	for k, v := range tags {
+		attrs = append(attrs, attributeFromField(tracelite.Field{Key: k, Value: v}))
+	}
+	return attrs
+}
+
+func attributeFromField(f tracelite.Field) attribute.KeyValue {
+	switch v := f.Value.(type) {
+	case string:
+		return attribute.String(f.Key, v)
+	case bool:
+		return attribute.Bool(f.Key, v)
+	case int:
+		return attribute.Int(f.Key, v)
+	case int64:
+		return attribute.Int64(f.Key, v)
+	case float64:
+		return attribute.Float64(f.Key, v)
+	default:
+		return attribute.String(f.Key, fmt.Sprintf("%v", v))
+	}
+}
+
+// Shutdown flushes any pending spans and closes the underlying OTLP
+// connection. Callers should invoke it during graceful shutdown.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	return e.provider.Shutdown(ctx)
+}
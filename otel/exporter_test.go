@@ -0,0 +1,73 @@
+package otel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OC0311/tracelite"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestExporter(t *testing.T) (*Exporter, *tracetest.InMemoryExporter) {
+	t.Helper()
+	mem := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(mem))
+	return &Exporter{
+		provider: provider,
+		tracer:   provider.Tracer("test"),
+	}, mem
+}
+
+func TestExportConvertsSubTracesAndSpans(t *testing.T) {
+	exp, mem := newTestExporter(t)
+
+	now := time.Now()
+	result := &tracelite.TraceResult{
+		TraceName: "my_request",
+		Tags:      map[string]any{"route": "/orders"},
+		TotalCost: 30,
+		TraceSet: []map[string]tracelite.TraceResultItem{
+			{
+				"db_query": {
+					TraceCost: 30,
+					Tags:      map[string]any{"table": "orders"},
+					List: [][]interface{}{
+						{"query", int64(20), "SELECT 1", now.Add(10 * time.Millisecond), "", 0},
+						{"scan", int64(10), "rows=1", now.Add(30 * time.Millisecond), "", 0},
+					},
+				},
+			},
+		},
+	}
+
+	if err := exp.Export(result); err != nil {
+		t.Fatalf("Export returned an error: %v", err)
+	}
+
+	spans := mem.GetSpans()
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+	if len(spans) != 4 { // root + db_query + query + scan
+		t.Fatalf("expected 4 spans, got %d: %v", len(spans), names)
+	}
+
+	var root, sub tracetest.SpanStub
+	for _, s := range spans {
+		switch s.Name {
+		case "my_request":
+			root = s
+		case "db_query":
+			sub = s
+		}
+	}
+	if root.Name == "" || sub.Name == "" {
+		t.Fatalf("expected both root and sub-trace spans, got %v", names)
+	}
+	if sub.Parent.SpanID() != root.SpanContext.SpanID() {
+		t.Error("expected db_query to be a child of the root span")
+	}
+}
@@ -0,0 +1,81 @@
+package tracelite
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Sampler decides whether a sub-trace should record anything. The decision
+// is made once, the moment BeginTrace or StartStep creates the sub-trace,
+// letting high-throughput callers leave tracing wired into hot paths
+// without paying the allocation cost of BeginTrace/Mark on sub-traces that
+// will be discarded. BeginTrace and Mark still take the trace's lock for
+// such sub-traces. Install one via WithSampler.
+type Sampler interface {
+	ShouldSample() bool
+}
+
+type samplerFunc func() bool
+
+func (f samplerFunc) ShouldSample() bool { return f() }
+
+// AlwaysSample returns a Sampler that always records, matching tracelite's
+// default behavior when no sampler is installed.
+func AlwaysSample() Sampler {
+	return samplerFunc(func() bool { return true })
+}
+
+// NeverSample returns a Sampler that never records.
+func NeverSample() Sampler {
+	return samplerFunc(func() bool { return false })
+}
+
+// RatioSampler returns a Sampler that records a fraction of sub-traces,
+// chosen independently at random for each decision. fraction is clamped to
+// [0, 1].
+func RatioSampler(fraction float64) Sampler {
+	if fraction <= 0 {
+		return NeverSample()
+	}
+	if fraction >= 1 {
+		return AlwaysSample()
+	}
+	return samplerFunc(func() bool {
+		return rand.Float64() < fraction
+	})
+}
+
+// rateLimitedSampler admits at most perSecond decisions each calendar
+// second, across however many goroutines call it concurrently.
+type rateLimitedSampler struct {
+	mu        sync.Mutex
+	perSecond int
+	second    int64
+	count     int
+}
+
+// RateLimitedSampler returns a Sampler that admits at most perSecond
+// sub-traces each second, mirroring a common production pattern of tracing
+// every request up to a fixed budget and dropping the rest.
+func RateLimitedSampler(perSecond int) Sampler {
+	if perSecond <= 0 {
+		return NeverSample()
+	}
+	return &rateLimitedSampler{perSecond: perSecond}
+}
+
+func (s *rateLimitedSampler) ShouldSample() bool {
+	now := time.Now().Unix()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now != s.second {
+		s.second = now
+		s.count = 0
+	}
+	if s.count >= s.perSecond {
+		return false
+	}
+	s.count++
+	return true
+}
@@ -0,0 +1,137 @@
+package tracelite
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRatioSamplerClampsToBoundaries(t *testing.T) {
+	if RatioSampler(0).ShouldSample() {
+		t.Error("expected RatioSampler(0) to never sample")
+	}
+	if RatioSampler(-1).ShouldSample() {
+		t.Error("expected RatioSampler(-1) to never sample")
+	}
+	if !RatioSampler(1).ShouldSample() {
+		t.Error("expected RatioSampler(1) to always sample")
+	}
+	if !RatioSampler(2).ShouldSample() {
+		t.Error("expected RatioSampler(2) to always sample")
+	}
+}
+
+func TestRatioSamplerApproximatesFraction(t *testing.T) {
+	s := RatioSampler(0.5)
+	const n = 10000
+	var sampled int
+	for i := 0; i < n; i++ {
+		if s.ShouldSample() {
+			sampled++
+		}
+	}
+	if sampled < n/4 || sampled > 3*n/4 {
+		t.Errorf("expected roughly half of %d draws to sample, got %d", n, sampled)
+	}
+}
+
+func TestRateLimitedSamplerCapsPerSecond(t *testing.T) {
+	s := RateLimitedSampler(3)
+	var sampled int
+	for i := 0; i < 10; i++ {
+		if s.ShouldSample() {
+			sampled++
+		}
+	}
+	if sampled != 3 {
+		t.Errorf("expected exactly 3 of 10 draws within the same second to sample, got %d", sampled)
+	}
+}
+
+func TestRateLimitedSamplerZeroOrNegativeNeverSamples(t *testing.T) {
+	if RateLimitedSampler(0).ShouldSample() {
+		t.Error("expected RateLimitedSampler(0) to never sample")
+	}
+	if RateLimitedSampler(-5).ShouldSample() {
+		t.Error("expected RateLimitedSampler(-5) to never sample")
+	}
+}
+
+func TestWithSamplerGatesBeginTrace(t *testing.T) {
+	trace := NewTrace("test_trace", WithSampler(NeverSample()))
+	trace.TraceOn()
+
+	trace.BeginTrace("subtrace1", nil)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	result := trace.Collect()
+	if result == nil {
+		t.Fatal("expected a non-nil result")
+	}
+	for _, set := range result.TraceSet {
+		if _, ok := set["subtrace1"]; ok {
+			t.Errorf("expected 'subtrace1' to be dropped by NeverSample, got %v", set)
+		}
+	}
+}
+
+func TestWithSamplerGatesNewStepTraceOnly(t *testing.T) {
+	trace := NewTrace("test_trace", WithSampler(NeverSample()))
+	trace.TraceOn()
+
+	parent := trace.StartStep(context.Background(), "parent")
+	child := trace.StartStep(parent.Context(context.Background()), "child")
+	child.End()
+	parent.End()
+
+	result := trace.Collect()
+	for _, set := range result.TraceSet {
+		if _, ok := set["parent"]; ok {
+			t.Errorf("expected 'parent' to be dropped by NeverSample, got %v", set)
+		}
+		if _, ok := set[""]; ok {
+			t.Errorf("expected the sample-rejected parent's discard to propagate to its child, not leak a \"\"-named sub-trace, got %v", set)
+		}
+	}
+	if len(result.TraceSet) != 0 {
+		t.Errorf("expected an empty TraceSet when the top-level step is sample-rejected, got %v", result.TraceSet)
+	}
+}
+
+func TestLazyMarkDefersExtensionInfoUntilCollect(t *testing.T) {
+	trace := NewTrace("test_trace")
+	trace.TraceOn()
+	trace.BeginTrace("subtrace1", nil)
+
+	var calls int
+	trace.LazyMark("subtrace1", "action1", func() string {
+		calls++
+		return "computed"
+	})
+	if calls != 0 {
+		t.Fatalf("expected extFn not to be called before Collect, got %d calls", calls)
+	}
+
+	result := trace.Collect()
+	if calls != 1 {
+		t.Fatalf("expected extFn to be called exactly once by Collect, got %d calls", calls)
+	}
+
+	var found bool
+	for _, set := range result.TraceSet {
+		item, ok := set["subtrace1"]
+		if !ok {
+			continue
+		}
+		for _, span := range item.List {
+			if span[0] == "action1" {
+				found = true
+				if span[2] != "computed" {
+					t.Errorf("expected extensionInfo %q, got %v", "computed", span[2])
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("expected to find the LazyMark span in the result")
+	}
+}
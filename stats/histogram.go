@@ -0,0 +1,54 @@
+package stats
+
+import "sync"
+
+// DefaultBucketBoundsMs is used by NewHistogram when no explicit bounds are
+// given: a coarse latency ladder suitable for typical request traces.
+var DefaultBucketBoundsMs = []int64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Histogram is a cumulative, fixed-bucket latency histogram. Unlike
+// ExpDecayReservoir it never discards observations, trading unbounded
+// lifetime counts for exact bucket membership.
+type Histogram struct {
+	mu     sync.Mutex
+	bounds []int64 // ascending upper bounds in milliseconds
+	counts []int64 // counts[i] = observations in (bounds[i-1], bounds[i]]; counts[len(bounds)] is the unbounded overflow bucket
+	count  int64
+	sum    int64
+}
+
+// NewHistogram creates a Histogram with the given ascending bucket upper
+// bounds in milliseconds, or DefaultBucketBoundsMs if boundsMs is empty.
+func NewHistogram(boundsMs []int64) *Histogram {
+	if len(boundsMs) == 0 {
+		boundsMs = DefaultBucketBoundsMs
+	}
+	return &Histogram{
+		bounds: boundsMs,
+		counts: make([]int64, len(boundsMs)+1),
+	}
+}
+
+// Observe records a single latency observation in milliseconds.
+func (h *Histogram) Observe(ms int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.count++
+	h.sum += ms
+	for i, bound := range h.bounds {
+		if ms <= bound {
+			h.counts[i]++
+			return
+		}
+	}
+	h.counts[len(h.counts)-1]++
+}
+
+// Snapshot returns the bucket upper bounds, the per-bucket observation
+// counts (one more entry than bounds, for the unbounded overflow bucket),
+// and the total count and sum of all observations.
+func (h *Histogram) Snapshot() (bounds []int64, counts []int64, count int64, sum int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]int64(nil), h.bounds...), append([]int64(nil), h.counts...), h.count, h.sum
+}
@@ -0,0 +1,33 @@
+package stats
+
+import "testing"
+
+func TestHistogramObserveAndSnapshot(t *testing.T) {
+	h := NewHistogram([]int64{10, 100})
+	h.Observe(5)
+	h.Observe(50)
+	h.Observe(500)
+
+	bounds, counts, count, sum := h.Snapshot()
+	if len(bounds) != 2 || len(counts) != 3 {
+		t.Fatalf("expected 2 bounds and 3 count buckets, got %d/%d", len(bounds), len(counts))
+	}
+	if counts[0] != 1 || counts[1] != 1 || counts[2] != 1 {
+		t.Errorf("expected one observation per bucket, got %v", counts)
+	}
+	if count != 3 {
+		t.Errorf("expected count 3, got %d", count)
+	}
+	if sum != 555 {
+		t.Errorf("expected sum 555, got %d", sum)
+	}
+}
+
+func TestHistogramDefaultBounds(t *testing.T) {
+	h := NewHistogram(nil)
+	h.Observe(1)
+	bounds, _, _, _ := h.Snapshot()
+	if len(bounds) != len(DefaultBucketBoundsMs) {
+		t.Errorf("expected default bounds to be used, got %d bounds", len(bounds))
+	}
+}
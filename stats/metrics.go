@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// MetricsHandler serves a Prometheus text-exposition-format /metrics page on
+// DefaultRegistry.
+func MetricsHandler() http.Handler {
+	return DefaultRegistry.MetricsHandler()
+}
+
+// MetricsHandler serves a Prometheus text-exposition-format /metrics page
+// for r: a cumulative histogram per family
+// (tracelite_trace_duration_milliseconds) and a rolling quantile summary per
+// family and window (tracelite_trace_duration_quantile_milliseconds).
+func (r *Registry) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		snapshots := r.Snapshot()
+
+		fmt.Fprintln(w, "# HELP tracelite_trace_duration_milliseconds Cumulative histogram of trace/sub-trace cost.")
+		fmt.Fprintln(w, "# TYPE tracelite_trace_duration_milliseconds histogram")
+		for _, snap := range snapshots {
+			var cumulative int64
+			for i, bound := range snap.HistogramBounds {
+				cumulative += snap.HistogramCounts[i]
+				fmt.Fprintf(w, "tracelite_trace_duration_milliseconds_bucket{family=%q,le=\"%d\"} %d\n", snap.Name, bound, cumulative)
+			}
+			cumulative += snap.HistogramCounts[len(snap.HistogramCounts)-1]
+			fmt.Fprintf(w, "tracelite_trace_duration_milliseconds_bucket{family=%q,le=\"+Inf\"} %d\n", snap.Name, cumulative)
+			fmt.Fprintf(w, "tracelite_trace_duration_milliseconds_sum{family=%q} %d\n", snap.Name, snap.TotalCostMs)
+			fmt.Fprintf(w, "tracelite_trace_duration_milliseconds_count{family=%q} %d\n", snap.Name, snap.TotalCount)
+		}
+
+		fmt.Fprintln(w, "# HELP tracelite_trace_duration_quantile_milliseconds Rolling p50/p90/p99 trace cost per window.")
+		fmt.Fprintln(w, "# TYPE tracelite_trace_duration_quantile_milliseconds summary")
+		for _, snap := range snapshots {
+			for _, ws := range snap.Windows {
+				fmt.Fprintf(w, "tracelite_trace_duration_quantile_milliseconds{family=%q,window=%q,quantile=\"0.5\"} %g\n", snap.Name, ws.Window, ws.P50)
+				fmt.Fprintf(w, "tracelite_trace_duration_quantile_milliseconds{family=%q,window=%q,quantile=\"0.9\"} %g\n", snap.Name, ws.Window, ws.P90)
+				fmt.Fprintf(w, "tracelite_trace_duration_quantile_milliseconds{family=%q,window=%q,quantile=\"0.99\"} %g\n", snap.Name, ws.Window, ws.P99)
+				fmt.Fprintf(w, "tracelite_trace_duration_window_count{family=%q,window=%q} %d\n", snap.Name, ws.Window, ws.Count)
+			}
+		}
+	})
+}
@@ -0,0 +1,170 @@
+// Package stats maintains per-family (Trace.Name and sub-trace name)
+// latency histograms and rolling time-series of trace counts and p50/p90/p99
+// latencies, following the histogram/time-series subsystem in
+// golang.org/x/net/trace. A Registry satisfies tracelite.Exporter, so it can
+// be installed directly via tracelite.WithExporter.
+package stats
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/OC0311/tracelite"
+)
+
+// windowSpecs define the rolling windows every family is tracked over, and
+// the ExpDecayReservoir alpha approximating each window's time horizon: a
+// larger alpha decays faster, biasing the reservoir toward more recent
+// samples.
+var windowSpecs = []struct {
+	name    string
+	minutes int
+	alpha   float64
+}{
+	{"1m", 1, 0.25},
+	{"10m", 10, 0.05},
+	{"1h", 60, 0.01},
+}
+
+// WindowSnapshot is one rolling window's observation count and latency
+// quantiles (in milliseconds).
+type WindowSnapshot struct {
+	Window string
+	Count  int64
+	P50    float64
+	P90    float64
+	P99    float64
+}
+
+// FamilySnapshot is the Registry's view of one trace family (Trace.Name and
+// sub-trace names share this namespace).
+type FamilySnapshot struct {
+	Name            string
+	HistogramBounds []int64 // ms, ascending
+	HistogramCounts []int64 // cumulative per-bucket counts, one longer than HistogramBounds for the unbounded overflow bucket
+	TotalCount      int64
+	TotalCostMs     int64
+	Windows         []WindowSnapshot
+}
+
+// family is a Registry's per-name bundle of tracking structures.
+type family struct {
+	histogram  *Histogram
+	counts     *rollingCounts
+	reservoirs []*ExpDecayReservoir // parallel to windowSpecs
+}
+
+func newFamily() *family {
+	f := &family{
+		histogram: NewHistogram(nil),
+		counts:    newRollingCounts(),
+	}
+	for _, w := range windowSpecs {
+		f.reservoirs = append(f.reservoirs, NewExpDecayReservoir(w.alpha))
+	}
+	return f
+}
+
+func (f *family) record(costMs int64) {
+	f.histogram.Observe(costMs)
+	f.counts.Record()
+	for _, r := range f.reservoirs {
+		r.Update(float64(costMs))
+	}
+}
+
+func (f *family) snapshot(name string) FamilySnapshot {
+	bounds, counts, total, sum := f.histogram.Snapshot()
+	snap := FamilySnapshot{
+		Name:            name,
+		HistogramBounds: bounds,
+		HistogramCounts: counts,
+		TotalCount:      total,
+		TotalCostMs:     sum,
+	}
+	for i, w := range windowSpecs {
+		qs := f.reservoirs[i].Quantiles(0.5, 0.9, 0.99)
+		snap.Windows = append(snap.Windows, WindowSnapshot{
+			Window: w.name,
+			Count:  f.counts.Sum(w.minutes),
+			P50:    qs[0],
+			P90:    qs[1],
+			P99:    qs[2],
+		})
+	}
+	return snap
+}
+
+// Registry is a process-wide collection of per-family latency histograms
+// and rolling time-series.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// DefaultRegistry is the Registry backing the package-level Record,
+// Snapshot, and MetricsHandler functions.
+var DefaultRegistry = NewRegistry()
+
+func (r *Registry) familyFor(name string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.families[name]
+	if !ok {
+		f = newFamily()
+		r.families[name] = f
+	}
+	return f
+}
+
+// Record adds one latency observation (in milliseconds) to the named
+// family's histogram, rolling counts, and quantile reservoirs.
+func (r *Registry) Record(name string, costMs int64) {
+	r.familyFor(name).record(costMs)
+}
+
+// Record adds name's observation to DefaultRegistry.
+func Record(name string, costMs int64) {
+	DefaultRegistry.Record(name, costMs)
+}
+
+// Export implements tracelite.Exporter, so a Registry can be installed
+// directly via tracelite.WithExporter: it records the top-level trace under
+// result.TraceName and every sub-trace under its own name.
+func (r *Registry) Export(result *tracelite.TraceResult) error {
+	r.Record(result.TraceName, result.TotalCost)
+	for _, set := range result.TraceSet {
+		for name, item := range set {
+			r.Record(name, item.TraceCost)
+		}
+	}
+	return nil
+}
+
+// Snapshot returns a name-ordered snapshot of every family the registry has
+// recorded an observation for.
+func (r *Registry) Snapshot() []FamilySnapshot {
+	r.mu.Lock()
+	fams := make(map[string]*family, len(r.families))
+	for name, f := range r.families {
+		fams[name] = f
+	}
+	r.mu.Unlock()
+
+	snaps := make([]FamilySnapshot, 0, len(fams))
+	for name, f := range fams {
+		snaps = append(snaps, f.snapshot(name))
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Name < snaps[j].Name })
+	return snaps
+}
+
+// Snapshot returns a name-ordered snapshot of DefaultRegistry.
+func Snapshot() []FamilySnapshot {
+	return DefaultRegistry.Snapshot()
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OC0311/tracelite"
+)
+
+func TestRegistryRecordAndSnapshot(t *testing.T) {
+	r := NewRegistry()
+	r.Record("my_family", 5)
+	r.Record("my_family", 50)
+
+	snaps := r.Snapshot()
+	if len(snaps) != 1 || snaps[0].Name != "my_family" {
+		t.Fatalf("expected a single 'my_family' snapshot, got %+v", snaps)
+	}
+	if snaps[0].TotalCount != 2 || snaps[0].TotalCostMs != 55 {
+		t.Errorf("expected TotalCount=2 TotalCostMs=55, got %+v", snaps[0])
+	}
+	for _, ws := range snaps[0].Windows {
+		if ws.Count != 2 {
+			t.Errorf("expected window %s to report count 2, got %d", ws.Window, ws.Count)
+		}
+	}
+}
+
+func TestRegistryExportRecordsTraceAndSubTraces(t *testing.T) {
+	r := NewRegistry()
+	result := &tracelite.TraceResult{
+		TraceName: "request",
+		TotalCost: 40,
+		TraceSet: []map[string]tracelite.TraceResultItem{
+			{"db_query": {TraceCost: 30}},
+		},
+	}
+
+	if err := r.Export(result); err != nil {
+		t.Fatalf("Export returned an error: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, snap := range r.Snapshot() {
+		names[snap.Name] = true
+	}
+	if !names["request"] || !names["db_query"] {
+		t.Errorf("expected both 'request' and 'db_query' families, got %v", names)
+	}
+}
+
+func TestMetricsHandlerRendersPrometheusFormat(t *testing.T) {
+	r := NewRegistry()
+	r.Record("my_family", 5)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	r.MetricsHandler().ServeHTTP(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `tracelite_trace_duration_milliseconds_bucket{family="my_family"`) {
+		t.Errorf("expected a histogram bucket line for my_family, got:\n%s", body)
+	}
+	if !strings.Contains(body, `tracelite_trace_duration_quantile_milliseconds{family="my_family"`) {
+		t.Errorf("expected a quantile summary line for my_family, got:\n%s", body)
+	}
+}
@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// reservoirSize bounds the number of samples an ExpDecayReservoir retains
+// regardless of how many observations are recorded, matching the default
+// used by Dropwizard Metrics' ExponentiallyDecayingReservoir.
+const reservoirSize = 1028
+
+// rescaleInterval bounds how long priorities are allowed to grow before
+// being renormalized, to avoid floating point overflow in long-running
+// processes. Five minutes keeps exp(alpha*elapsed) finite for any alpha up
+// to 2, well above the largest alpha in windowSpecs (0.25).
+const rescaleInterval = 5 * time.Minute
+
+// ExpDecayReservoir is a fixed-size, exponentially-decaying reservoir of
+// latency observations used to estimate quantiles with memory bounded
+// regardless of throughput. Each sample is keyed by a priority that decays
+// exponentially with age (Cormode et al., "Forward Decay: A Practical Time
+// Decay Model for Streaming Systems"), so once the reservoir is full new
+// samples evict the oldest-weighted one rather than a uniformly random one
+// -- biasing the estimate towards recent observations. A larger alpha
+// decays faster, approximating a shorter rolling time window.
+type ExpDecayReservoir struct {
+	mu          sync.Mutex
+	alpha       float64
+	rng         *rand.Rand
+	startTime   time.Time
+	nextRescale time.Time
+	values      map[float64]float64 // priority -> observed value
+	count       int64
+}
+
+// NewExpDecayReservoir creates a reservoir decaying at the given alpha.
+func NewExpDecayReservoir(alpha float64) *ExpDecayReservoir {
+	now := time.Now()
+	return &ExpDecayReservoir{
+		alpha:       alpha,
+		rng:         rand.New(rand.NewSource(now.UnixNano())),
+		startTime:   now,
+		nextRescale: now.Add(rescaleInterval),
+		values:      make(map[float64]float64, reservoirSize),
+	}
+}
+
+func (r *ExpDecayReservoir) weight(now time.Time) float64 {
+	return math.Exp(r.alpha * now.Sub(r.startTime).Seconds())
+}
+
+// Update records a new observation.
+func (r *ExpDecayReservoir) Update(value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.rescaleIfDueLocked(now)
+	r.count++
+
+	rnd := r.rng.Float64()
+	for rnd == 0 {
+		rnd = r.rng.Float64()
+	}
+	priority := r.weight(now) / rnd
+
+	if len(r.values) < reservoirSize {
+		r.values[priority] = value
+		return
+	}
+
+	minPriority := math.Inf(1)
+	for p := range r.values {
+		if p < minPriority {
+			minPriority = p
+		}
+	}
+	if priority > minPriority {
+		delete(r.values, minPriority)
+		r.values[priority] = value
+	}
+}
+
+// rescaleIfDueLocked renormalizes every retained priority relative to a
+// fresh start time once rescaleInterval has elapsed. Called with mu held.
+func (r *ExpDecayReservoir) rescaleIfDueLocked(now time.Time) {
+	if now.Before(r.nextRescale) {
+		return
+	}
+	oldStart := r.startTime
+	r.startTime = now
+	r.nextRescale = now.Add(rescaleInterval)
+
+	factor := math.Exp(-r.alpha * now.Sub(oldStart).Seconds())
+	rescaled := make(map[float64]float64, len(r.values))
+	for priority, value := range r.values {
+		rescaled[priority*factor] = value
+	}
+	r.values = rescaled
+}
+
+// Quantiles returns the values at each of qs (each in [0, 1]), computed over
+// the reservoir's current sample set. It returns 0 for every quantile if no
+// observations have been recorded.
+func (r *ExpDecayReservoir) Quantiles(qs ...float64) []float64 {
+	r.mu.Lock()
+	values := make([]float64, 0, len(r.values))
+	for _, v := range r.values {
+		values = append(values, v)
+	}
+	r.mu.Unlock()
+
+	sort.Float64s(values)
+	out := make([]float64, len(qs))
+	if len(values) == 0 {
+		return out
+	}
+	for i, q := range qs {
+		idx := int(q * float64(len(values)))
+		if idx >= len(values) {
+			idx = len(values) - 1
+		}
+		out[i] = values[idx]
+	}
+	return out
+}
+
+// Count returns the number of observations ever recorded, which may exceed
+// the number currently retained in the reservoir.
+func (r *ExpDecayReservoir) Count() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
@@ -0,0 +1,40 @@
+package stats
+
+import "testing"
+
+func TestExpDecayReservoirQuantiles(t *testing.T) {
+	r := NewExpDecayReservoir(0.05)
+	for i := 1; i <= 100; i++ {
+		r.Update(float64(i))
+	}
+
+	if r.Count() != 100 {
+		t.Errorf("expected Count to report 100 observations, got %d", r.Count())
+	}
+
+	qs := r.Quantiles(0.5, 0.99)
+	if qs[0] < 30 || qs[0] > 70 {
+		t.Errorf("expected p50 roughly in the middle of 1..100, got %v", qs[0])
+	}
+	if qs[1] < qs[0] {
+		t.Errorf("expected p99 (%v) >= p50 (%v)", qs[1], qs[0])
+	}
+}
+
+func TestExpDecayReservoirBoundedSize(t *testing.T) {
+	r := NewExpDecayReservoir(0.05)
+	for i := 0; i < reservoirSize*3; i++ {
+		r.Update(float64(i))
+	}
+	if len(r.values) > reservoirSize {
+		t.Errorf("expected reservoir to stay bounded at %d, got %d", reservoirSize, len(r.values))
+	}
+}
+
+func TestExpDecayReservoirEmpty(t *testing.T) {
+	r := NewExpDecayReservoir(0.05)
+	qs := r.Quantiles(0.5, 0.9)
+	if qs[0] != 0 || qs[1] != 0 {
+		t.Errorf("expected quantiles of an empty reservoir to be 0, got %v", qs)
+	}
+}
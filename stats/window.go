@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+// windowSlots is the number of one-minute slots kept in a rollingCounts ring
+// buffer, covering the largest supported window (1 hour).
+const windowSlots = 60
+
+// rollingCounts tracks per-minute observation counts in a fixed-size ring
+// buffer, so Sum can report exact totals over the last 1, 10, or 60 minutes
+// with memory bounded to windowSlots regardless of throughput.
+type rollingCounts struct {
+	mu      sync.Mutex
+	slots   [windowSlots]int64
+	slotMin int64 // unix-minute that slots[head] covers
+	head    int
+}
+
+func newRollingCounts() *rollingCounts {
+	return &rollingCounts{slotMin: time.Now().Unix() / 60}
+}
+
+// advanceLocked rotates the ring forward to nowMin, zeroing any slots that
+// fall out of the window along the way. Called with mu held.
+func (c *rollingCounts) advanceLocked(nowMin int64) {
+	elapsed := nowMin - c.slotMin
+	if elapsed <= 0 {
+		return
+	}
+	if elapsed >= windowSlots {
+		c.slots = [windowSlots]int64{}
+	} else {
+		for i := int64(0); i < elapsed; i++ {
+			c.head = (c.head + 1) % windowSlots
+			c.slots[c.head] = 0
+		}
+	}
+	c.slotMin = nowMin
+}
+
+// Record increments the counter for the current minute.
+func (c *rollingCounts) Record() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advanceLocked(time.Now().Unix() / 60)
+	c.slots[c.head]++
+}
+
+// Sum returns the total count over the last minutes minutes, including the
+// current, still-accumulating minute.
+func (c *rollingCounts) Sum(minutes int) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advanceLocked(time.Now().Unix() / 60)
+	if minutes > windowSlots {
+		minutes = windowSlots
+	}
+	var total int64
+	idx := c.head
+	for i := 0; i < minutes; i++ {
+		total += c.slots[idx]
+		idx = (idx - 1 + windowSlots) % windowSlots
+	}
+	return total
+}
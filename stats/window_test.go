@@ -0,0 +1,42 @@
+package stats
+
+import "testing"
+
+func TestRollingCountsAccumulatesWithinAMinute(t *testing.T) {
+	c := newRollingCounts()
+	c.Record()
+	c.Record()
+	c.Record()
+
+	if got := c.Sum(1); got != 3 {
+		t.Errorf("expected Sum(1) to be 3, got %d", got)
+	}
+	if got := c.Sum(60); got != 3 {
+		t.Errorf("expected Sum(60) to also be 3, got %d", got)
+	}
+}
+
+func TestRollingCountsAdvanceEvictsOldSlots(t *testing.T) {
+	c := newRollingCounts()
+	c.Record()
+	c.Record()
+
+	// Simulate a full rotation: every slot should be cleared.
+	c.advanceLocked(c.slotMin + windowSlots)
+
+	if got := c.Sum(windowSlots); got != 0 {
+		t.Errorf("expected counts to be evicted after a full rotation, got %d", got)
+	}
+}
+
+func TestRollingCountsPartialAdvanceKeepsRecentSlots(t *testing.T) {
+	c := newRollingCounts()
+	c.Record()
+	c.advanceLocked(c.slotMin + 1)
+	c.Record()
+	c.Record()
+
+	if got := c.Sum(2); got != 3 {
+		t.Errorf("expected Sum(2) to include both minutes (1+2), got %d", got)
+	}
+}
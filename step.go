@@ -0,0 +1,120 @@
+package tracelite
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// stepSeq hands out process-wide unique span ids for steps.
+var stepSeq int64
+
+// stepCtxKey is the context key under which the currently active *Step is
+// stored, so that nested StartStep calls can discover their parent.
+type stepCtxKey struct{}
+
+// Step represents one nested operation started via (*Trace).StartStep. It is
+// closed by calling End, which records its elapsed cost as a span on the
+// sub-trace it belongs to.
+type Step struct {
+	trace     *Trace
+	discard   bool
+	traceName string
+	id        string
+	parentID  string
+	depth     int
+	action    string
+	fields    []Field
+	startAt   time.Time
+}
+
+// StartStep begins a new step named action and returns a *Step that must be
+// closed with End. If ctx carries a parent *Step (see Step.Context), the new
+// step is recorded as its child and appended to the same enclosing sub-trace;
+// otherwise action is used as the name of a new top-level sub-trace, like
+// BeginTrace but without a "Begin" marker span. StartStep is a no-op (the returned Step's End does nothing)
+// when tracing is off.
+func (t *Trace) StartStep(ctx context.Context, action string, fields ...Field) *Step {
+	parent, _ := ctx.Value(stepCtxKey{}).(*Step)
+
+	traceName := action
+	depth := 0
+	parentID := ""
+	if parent != nil {
+		traceName = parent.traceName
+		depth = parent.depth + 1
+		parentID = parent.id
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if !t.openTrace {
+		return &Step{trace: t, discard: true}
+	}
+	if parent != nil && parent.discard {
+		// The parent step was itself dropped (trace off or sample-rejected);
+		// propagate the discard instead of recording this child under a
+		// phantom traceName inherited from the discarded parent.
+		return &Step{trace: t, discard: true}
+	}
+	if _, ok := t.traceList[traceName]; !ok {
+		if parent == nil && !t.shouldSampleLocked() {
+			return &Step{trace: t, discard: true}
+		}
+		t.traceList[traceName] = trace{
+			name: traceName,
+			tags: make(map[string]any),
+			list: make([]span, 0),
+		}
+	}
+
+	return &Step{
+		trace:     t,
+		traceName: traceName,
+		id:        fmt.Sprintf("%s-%d", traceName, atomic.AddInt64(&stepSeq, 1)),
+		parentID:  parentID,
+		depth:     depth,
+		action:    action,
+		fields:    fields,
+		startAt:   time.Now(),
+	}
+}
+
+// Context returns a copy of ctx that carries s, so that a StartStep call made
+// with the returned context is recorded as a child of s.
+func (s *Step) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stepCtxKey{}, s)
+}
+
+// End records the step's elapsed time as a span on its enclosing sub-trace.
+// It is safe to call End more than once; only the first call has an effect.
+func (s *Step) End() {
+	if s == nil || s.discard || s.trace == nil {
+		return
+	}
+	cost := time.Since(s.startAt).Milliseconds()
+
+	t := s.trace
+	t.Lock()
+	defer t.Unlock()
+	s.discard = true
+	if !t.openTrace {
+		return
+	}
+	tr, ok := t.traceList[s.traceName]
+	if !ok {
+		return
+	}
+	tr.list = append(tr.list, span{
+		id:       s.id,
+		parentID: s.parentID,
+		depth:    s.depth,
+		action:   s.action,
+		fields:   s.fields,
+		cost:     cost,
+		hasCost:  true,
+		martAt:   time.Now().UTC(),
+	})
+	t.traceList[s.traceName] = tr
+}
@@ -0,0 +1,145 @@
+package tracelite_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OC0311/tracelite"
+	"github.com/OC0311/tracelite/traceutil"
+)
+
+func TestStartStepNesting(t *testing.T) {
+	tr := tracelite.NewTrace("test_trace")
+	tr.TraceOn()
+
+	ctx := traceutil.NewContext(context.Background(), tr)
+
+	parent := tr.StartStep(ctx, "parent")
+	child := tr.StartStep(parent.Context(ctx), "child")
+	child.End()
+	parent.End()
+
+	result := tr.Collect()
+	if result == nil {
+		t.Fatal("expected a non-nil result")
+	}
+	var list []map[string]tracelite.TraceResultItem
+	for _, set := range result.TraceSet {
+		if _, ok := set["parent"]; ok {
+			list = append(list, set)
+		}
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected a single 'parent' sub-trace, got %d", len(list))
+	}
+	spans := list[0]["parent"].List
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	var parentDepth, parentID int
+	var childParentID, childDepth interface{}
+	for _, s := range spans {
+		action := s[0].(string)
+		id := s[4]
+		depth := s[5]
+		if action == "parent" {
+			parentDepth = depth.(int)
+			parentID = 0
+			_ = parentID
+			if id != "" {
+				t.Errorf("expected parent span to have no parentID, got %v", id)
+			}
+		} else {
+			childParentID = id
+			childDepth = depth
+		}
+	}
+	if childParentID == "" {
+		t.Error("expected child span to carry a non-empty parentID")
+	}
+	if childDepth.(int) != parentDepth+1 {
+		t.Errorf("expected child depth %d, got %v", parentDepth+1, childDepth)
+	}
+}
+
+func TestStartStepNestingDoesNotDoubleCountTotalCost(t *testing.T) {
+	tr := tracelite.NewTrace("test_trace")
+	tr.TraceOn()
+
+	ctx := traceutil.NewContext(context.Background(), tr)
+
+	parent := tr.StartStep(ctx, "parent")
+	time.Sleep(5 * time.Millisecond)
+	child := tr.StartStep(parent.Context(ctx), "child")
+	time.Sleep(5 * time.Millisecond)
+	child.End()
+	parent.End()
+
+	result := tr.Collect()
+	if result == nil {
+		t.Fatal("expected a non-nil result")
+	}
+	// The child's cost is wholly contained within the parent's elapsed time,
+	// so TotalCost should reflect only the parent's wall-clock cost, not the
+	// sum of parent and child.
+	for _, set := range result.TraceSet {
+		if item, ok := set["parent"]; ok {
+			var parentCost int64
+			for _, span := range item.List {
+				if span[0] == "parent" {
+					parentCost = span[1].(int64)
+				}
+			}
+			if item.TraceCost != parentCost {
+				t.Errorf("expected TraceCost %d to equal the parent span's own cost %d, not parent+child", item.TraceCost, parentCost)
+			}
+		}
+	}
+	if result.TotalCost >= 15 {
+		t.Errorf("expected TotalCost to count the parent span once, got %dms (suggests parent+child were summed)", result.TotalCost)
+	}
+}
+
+func TestStartStepConcurrentGoroutines(t *testing.T) {
+	tr := tracelite.NewTrace("test_trace")
+	tr.TraceOn()
+	ctx := traceutil.NewContext(context.Background(), tr)
+
+	const n = 20
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			trace := traceutil.FromContext(ctx)
+			step := trace.StartStep(ctx, "concurrent")
+			step.End()
+		}()
+	}
+	wg.Wait()
+
+	result := tr.Collect()
+	for _, set := range result.TraceSet {
+		if item, ok := set["concurrent"]; ok {
+			if len(item.List) != n {
+				t.Errorf("expected %d spans, got %d", n, len(item.List))
+			}
+			return
+		}
+	}
+	t.Fatal("expected a 'concurrent' sub-trace to be created")
+}
+
+func TestStartStepWhenTraceOff(t *testing.T) {
+	tr := tracelite.NewTrace("test_trace")
+	step := tr.StartStep(context.Background(), "noop")
+	step.End()
+
+	result := tr.Collect()
+	if result != nil {
+		t.Error("expected Collect to return nil while tracing is off")
+	}
+}
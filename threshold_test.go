@@ -0,0 +1,96 @@
+package tracelite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWithTraceThresholdDiscardsFastTraces(t *testing.T) {
+	trace := NewTrace("test_trace", WithTraceThreshold(time.Hour))
+	trace.TraceOn()
+
+	trace.BeginTrace("subtrace1", nil)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	if result := trace.Collect(); result != nil {
+		t.Errorf("expected Collect to discard a trace below the threshold, got %+v", result)
+	}
+}
+
+func TestWithStepThresholdFiltersSpans(t *testing.T) {
+	trace := NewTrace("test_trace", WithStepThreshold(time.Hour))
+	trace.TraceOn()
+
+	trace.BeginTrace("subtrace1", nil)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	result := trace.Collect()
+	if result == nil {
+		t.Fatal("expected a non-nil result")
+	}
+	for _, set := range result.TraceSet {
+		if item, ok := set["subtrace1"]; ok && len(item.List) != 0 {
+			t.Errorf("expected fast spans to be filtered out, got %v", item.List)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	trace := NewTrace("test_trace")
+	trace.TraceOn()
+	trace.BeginTrace("subtrace1", nil)
+
+	trace.Fields(Field{Key: "user_id", Value: 42})
+
+	result := trace.Collect()
+	if len(result.Fields) != 1 || result.Fields[0].Key != "user_id" {
+		t.Errorf("expected Fields to be carried through Collect, got %v", result.Fields)
+	}
+}
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestLogIfLong(t *testing.T) {
+	trace := NewTrace("test_trace")
+	trace.TraceOn()
+	trace.BeginTrace("subtrace1", nil)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	logger := &recordingLogger{}
+	trace.LogIfLong(logger, time.Hour)
+	if len(logger.lines) != 0 {
+		t.Errorf("expected no log lines below threshold, got %v", logger.lines)
+	}
+
+	trace.LogIfLong(logger, 0)
+	if len(logger.lines) != 1 {
+		t.Errorf("expected one log line at a zero threshold, got %v", logger.lines)
+	}
+}
+
+func TestLogIfLongIgnoresTraceThreshold(t *testing.T) {
+	// A WithTraceThreshold high enough that Collect would discard this trace
+	// entirely must not stop LogIfLong from logging against its own, lower,
+	// ad-hoc threshold.
+	trace := NewTrace("test_trace", WithTraceThreshold(time.Hour))
+	trace.TraceOn()
+	trace.BeginTrace("subtrace1", nil)
+	trace.Mark("subtrace1", "action1", "ext1")
+
+	if result := trace.Collect(); result != nil {
+		t.Fatalf("expected Collect to discard below WithTraceThreshold, got %+v", result)
+	}
+
+	logger := &recordingLogger{}
+	trace.LogIfLong(logger, 0)
+	if len(logger.lines) != 1 {
+		t.Errorf("expected LogIfLong to log regardless of WithTraceThreshold, got %v", logger.lines)
+	}
+}
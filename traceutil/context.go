@@ -0,0 +1,34 @@
+// Package traceutil provides context.Context helpers for propagating a
+// *tracelite.Trace across function and goroutine boundaries, mirroring the
+// traceutil package in etcd and the net/context conventions used by
+// OpenTelemetry.
+package traceutil
+
+import (
+	"context"
+
+	"github.com/OC0311/tracelite"
+)
+
+// traceCtxKey is the context key under which a *tracelite.Trace is stored.
+type traceCtxKey struct{}
+
+// NewContext returns a copy of ctx that carries tr, retrievable later with
+// FromContext.
+func NewContext(ctx context.Context, tr *tracelite.Trace) context.Context {
+	return context.WithValue(ctx, traceCtxKey{}, tr)
+}
+
+// FromContext returns the *tracelite.Trace stored in ctx by NewContext, or
+// nil if ctx carries none.
+func FromContext(ctx context.Context) *tracelite.Trace {
+	tr, _ := ctx.Value(traceCtxKey{}).(*tracelite.Trace)
+	return tr
+}
+
+// TODO returns a non-nil, empty context for callers that don't yet have one
+// to propagate but expect to add a Trace to it later, mirroring
+// context.TODO.
+func TODO() context.Context {
+	return context.TODO()
+}
@@ -0,0 +1,27 @@
+package traceutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OC0311/tracelite"
+)
+
+func TestNewContextAndFromContext(t *testing.T) {
+	tr := tracelite.NewTrace("test_trace")
+
+	if got := FromContext(context.Background()); got != nil {
+		t.Errorf("expected nil Trace from an empty context, got %v", got)
+	}
+
+	ctx := NewContext(context.Background(), tr)
+	if got := FromContext(ctx); got != tr {
+		t.Errorf("expected FromContext to return the stored Trace")
+	}
+}
+
+func TestTODO(t *testing.T) {
+	if TODO() == nil {
+		t.Error("expected TODO to return a non-nil context")
+	}
+}